@@ -0,0 +1,58 @@
+// Command devlog reads JSON or logfmt log lines from stdin and re-emits
+// them through devlog.DevLogHandler for colorized, human-readable output.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/wvan1901/wicho/devlog"
+	"github.com/wvan1901/wicho/devlog/scanner"
+)
+
+func main() {
+	var (
+		skip       string
+		keep       string
+		timeFormat string
+	)
+	flag.StringVar(&skip, "skip", "", "comma-separated list of keys to drop")
+	flag.StringVar(&keep, "keep", "", "comma-separated list of keys to keep, overrides -skip")
+	flag.StringVar(&timeFormat, "time-format", "", "time layout used to render each line's timestamp")
+	flag.Parse()
+
+	opts := &devlog.Options{TimeFormat: timeFormat}
+	h := devlog.New(os.Stdout, opts, nil)
+
+	filter := scanner.KeyFilter{
+		Skip: toSet(skip),
+		Keep: toSet(keep),
+	}
+
+	ctx := context.Background()
+	err := scanner.Scan(os.Stdin, filter, func(r slog.Record) error {
+		return h.Handle(ctx, r)
+	})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "devlog:", err)
+		os.Exit(1)
+	}
+}
+
+func toSet(csv string) map[string]bool {
+	if csv == "" {
+		return nil
+	}
+	set := make(map[string]bool)
+	for _, key := range strings.Split(csv, ",") {
+		key = strings.TrimSpace(key)
+		if key != "" {
+			set[key] = true
+		}
+	}
+	return set
+}
@@ -0,0 +1,47 @@
+package devlog
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+var benchCtx = context.Background()
+
+func BenchmarkHandle(b *testing.B) {
+	h := New(io.Discard, &Options{NoColor: true}, nil)
+	r := slog.NewRecord(time.Now(), slog.LevelInfo, "benchmark message", 0)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if err := h.Handle(benchCtx, r); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkHandleAttrs10(b *testing.B) {
+	h := New(io.Discard, &Options{NoColor: true}, nil)
+	r := slog.NewRecord(time.Now(), slog.LevelInfo, "benchmark message", 0)
+	r.AddAttrs(
+		slog.String("method", "GET"),
+		slog.String("path", "/api/v1/widgets"),
+		slog.Int("status", 200),
+		slog.Duration("duration", 12*time.Millisecond),
+		slog.Bool("cached", false),
+		slog.Float64("ratio", 0.987),
+		slog.Uint64("bytes", 4096),
+		slog.Any("error", nil),
+		slog.String("request_id", "c1a2b3d4"),
+		slog.String("user_agent", "devlog-bench/1.0"),
+	)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if err := h.Handle(benchCtx, r); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -5,9 +5,11 @@ import (
 	"fmt"
 	"io"
 	"log/slog"
+	"os"
 	"runtime"
 	"strconv"
 	"sync"
+	"time"
 )
 
 const (
@@ -49,10 +51,32 @@ const (
 )
 
 type DevLogHandler struct {
-	opts Options
-	out  io.Writer
-	goas []groupOrAttrs
-	mu   *sync.Mutex
+	opts    Options
+	out     io.Writer
+	goas    []groupOrAttrs
+	mu      *sync.Mutex
+	noColor bool
+	// prefixOut is the fully rendered "Prefix " text, precomputed once so
+	// Handle never re-colors it.
+	prefixOut string
+	// lvlLabel holds the precomputed, colored label for each of the four
+	// standard levels, indexed by lvlLabelIndex.
+	lvlLabel [4]string
+}
+
+// lvlLabelIndex maps a standard slog.Level to its slot in lvlLabel.
+func lvlLabelIndex(lvl slog.Level) (int, bool) {
+	switch lvl {
+	case slog.LevelDebug:
+		return 0, true
+	case slog.LevelInfo:
+		return 1, true
+	case slog.LevelWarn:
+		return 2, true
+	case slog.LevelError:
+		return 3, true
+	}
+	return 0, false
 }
 
 type Options struct {
@@ -66,6 +90,32 @@ type Options struct {
 	theme Theme
 	// Custom Prefix
 	Prefix string
+	// NoColor forces ANSI escapes off, regardless of whether out is a
+	// terminal. Takes precedence over ForceColor.
+	NoColor bool
+	// ForceColor forces ANSI escapes on, even if out is not a terminal.
+	ForceColor bool
+	// ReplaceAttr, if set, is called on every attribute before it is
+	// rendered, including the built-in time/level/message/source attrs.
+	// It has the same semantics as [slog.HandlerOptions.ReplaceAttr]:
+	// groups is the path of currently open groups (outermost first), and
+	// an attr whose returned Key is empty is dropped.
+	ReplaceAttr func(groups []string, a slog.Attr) slog.Attr
+	// DurationFormat, if set, overrides how slog.KindDuration values are
+	// rendered. Defaults to time.Duration.String.
+	DurationFormat func(d time.Duration) string
+	// TimeFormat is the layout used to render the record's time, in the
+	// format expected by [time.Time.Format]. Defaults to TIME_FORMAT.
+	TimeFormat string
+	// AttrOrder lists attribute keys that should be rendered first, in
+	// the given order; any remaining attrs on the record follow in their
+	// original insertion order. Only applies to the attrs passed to the
+	// logging call itself, not to grouped or WithAttrs attrs.
+	AttrOrder []string
+	// LevelNames overrides the rendered label for specific levels, e.g.
+	// mapping slog.LevelError+4 to "FATAL". Levels without an entry fall
+	// back to the standard label, or a bucket+delta label for custom ones.
+	LevelNames map[slog.Level]string
 }
 
 type Theme struct {
@@ -74,8 +124,14 @@ type Theme struct {
 	Time        color
 	Bool        color
 	Int         color
+	Uint        color
+	Float       color
+	Duration    color
+	Any         color
 	Group       color
 	AttrDefault color
+	// Error values rendered through KindAny
+	ErrorAttr color
 	// Log Levels
 	Debug color
 	Info  color
@@ -101,6 +157,9 @@ func New(out io.Writer, opts *Options, theme *Theme) *DevLogHandler {
 	if h.opts.Level == nil {
 		h.opts.Level = slog.LevelInfo
 	}
+	if h.opts.TimeFormat == "" {
+		h.opts.TimeFormat = TIME_FORMAT
+	}
 
 	if theme == nil {
 		// Set the default theme
@@ -109,8 +168,13 @@ func New(out io.Writer, opts *Options, theme *Theme) *DevLogHandler {
 			Time:        color{Fg: ANSI_FG_BLACK, Bg: ANSI_BG_LIGHTGREEN},
 			Bool:        color{Fg: ANSI_FG_LIGHTRED, Bg: ANSI_BG_BLACK},
 			Int:         color{Fg: ANSI_FG_LIGHTCYAN, Bg: ANSI_BG_BLACK},
+			Uint:        color{Fg: ANSI_FG_LIGHTCYAN, Bg: ANSI_BG_BLACK},
+			Float:       color{Fg: ANSI_FG_LIGHTCYAN, Bg: ANSI_BG_BLACK},
+			Duration:    color{Fg: ANSI_FG_MAGENTA, Bg: ANSI_BG_BLACK},
+			Any:         color{Fg: ANSI_FG_LIGHTGREEN, Bg: ANSI_BG_BLACK},
 			Group:       color{Fg: ANSI_FG_WHITE, Bg: ANSI_BG_BLUE},
 			AttrDefault: color{Fg: ANSI_FG_LIGHTGREEN, Bg: ANSI_BG_BLACK},
+			ErrorAttr:   color{Fg: ANSI_FG_LIGHTRED, Bg: ANSI_BG_BLACK},
 			Debug:       color{Fg: ANSI_FG_BLACK, Bg: ANSI_BG_DARKGRAY},
 			Info:        color{Fg: ANSI_FG_BLACK, Bg: ANSI_BG_CYAN},
 			Warn:        color{Fg: ANSI_FG_BLACK, Bg: ANSI_BG_LIGHTYELLOW},
@@ -120,34 +184,103 @@ func New(out io.Writer, opts *Options, theme *Theme) *DevLogHandler {
 		}
 	}
 	h.opts.theme = *theme
+	h.noColor = !wantColor(out, h.opts)
+
+	if h.opts.Prefix != "" {
+		h.prefixOut = h.colorSimple(h.opts.theme.Prefix, h.opts.Prefix) + " "
+	}
+	h.lvlLabel[0] = h.colorSimple(h.opts.theme.Debug, " DEBUG ")
+	h.lvlLabel[1] = h.colorSimple(h.opts.theme.Info, " INFO  ")
+	h.lvlLabel[2] = h.colorSimple(h.opts.theme.Warn, " WARN  ")
+	h.lvlLabel[3] = h.colorSimple(h.opts.theme.Error, " ERROR ")
 
 	return h
 }
 
+// wantColor reports whether out should receive ANSI color escapes, honoring
+// Options.NoColor / Options.ForceColor and the NO_COLOR / FORCE_COLOR env
+// vars before falling back to detecting whether out is a terminal.
+func wantColor(out io.Writer, opts Options) bool {
+	if opts.NoColor {
+		return false
+	}
+	if opts.ForceColor {
+		return true
+	}
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	if os.Getenv("FORCE_COLOR") != "" {
+		return true
+	}
+	return isTerminal(out)
+}
+
+// isTerminal reports whether out is a character device, e.g. a terminal as
+// opposed to a file or pipe.
+func isTerminal(out io.Writer) bool {
+	f, ok := out.(*os.File)
+	if !ok {
+		return false
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return fi.Mode()&os.ModeCharDevice != 0
+}
+
 func (h *DevLogHandler) Enabled(ctx context.Context, level slog.Level) bool {
 	return level >= h.opts.Level.Level()
 }
 
+// maxPooledBufferSize is the largest buffer capacity put back into bufPool;
+// bigger ones are left for the GC so one oversized record doesn't bloat the
+// pool's steady-state memory.
+const maxPooledBufferSize = 16 << 10
+
+// buffer is a pooled []byte used to render a single record without
+// allocating for every field.
+type buffer []byte
+
+var bufPool = sync.Pool{
+	New: func() any {
+		b := make(buffer, 0, 1024)
+		return &b
+	},
+}
+
+func newBuffer() *buffer {
+	return bufPool.Get().(*buffer)
+}
+
+func (b *buffer) free() {
+	if cap(*b) <= maxPooledBufferSize {
+		*b = (*b)[:0]
+		bufPool.Put(b)
+	}
+}
+
 func (h *DevLogHandler) Handle(ctx context.Context, r slog.Record) error {
-	buf := make([]byte, 0, 1024)
-	if h.opts.Prefix != "" {
-		prefixStr := colorSimple(h.opts.theme.Prefix, h.opts.Prefix)
-		buf = fmt.Append(buf, prefixStr+" ")
+	buf := newBuffer()
+	defer buf.free()
+
+	if h.prefixOut != "" {
+		*buf = append(*buf, h.prefixOut...)
 	}
 	if !r.Time.IsZero() {
-		buf = h.appendAttr(buf, slog.Time(slog.TimeKey, r.Time))
+		*buf = h.appendAttr(*buf, nil, slog.Time(slog.TimeKey, r.Time))
 	}
 
-	buf = fmt.Append(buf, handleLvl(r.Level, h.opts.theme)+" ")
+	*buf = h.appendAttr(*buf, nil, slog.Any(slog.LevelKey, r.Level))
 
 	if r.PC != 0 && h.opts.AddSource {
 		fs := runtime.CallersFrames([]uintptr{r.PC})
 		f, _ := fs.Next()
-		sourceStr := fmt.Sprintf("%s:%d ", f.File, f.Line)
-		colorVal := colorSimple(h.opts.theme.SourceFile, sourceStr)
-		buf = fmt.Append(buf, colorVal)
+		src := &slog.Source{Function: f.Function, File: f.File, Line: f.Line}
+		*buf = h.appendAttr(*buf, nil, slog.Any(slog.SourceKey, src))
 	}
-	buf = h.appendAttr(buf, slog.String(slog.MessageKey, r.Message))
+	*buf = h.appendAttr(*buf, nil, slog.String(slog.MessageKey, r.Message))
 
 	// Handle state from WithGroup and WithAttrs.
 	goas := h.goas
@@ -157,50 +290,146 @@ func (h *DevLogHandler) Handle(ctx context.Context, r slog.Record) error {
 			goas = goas[:len(goas)-1]
 		}
 	}
+	var groups []string
 	for _, goa := range goas {
 		if goa.group != "" {
-			buf = fmt.Appendf(buf, "%*s%s:\n", 4, "", goa.group)
+			*buf = fmt.Appendf(*buf, "%*s%s:\n", 4, "", goa.group)
+			groups = append(groups, goa.group)
 		} else {
 			for _, a := range goa.attrs {
-				buf = h.appendAttr(buf, a)
+				*buf = h.appendAttr(*buf, groups, a)
 			}
 		}
 	}
+	attrs := make([]slog.Attr, 0, r.NumAttrs())
 	r.Attrs(func(a slog.Attr) bool {
-		buf = h.appendAttr(buf, a)
+		attrs = append(attrs, a)
 		return true
 	})
+	for _, a := range h.orderAttrs(attrs) {
+		*buf = h.appendAttr(*buf, groups, a)
+	}
 
-	buf = append(buf, "\n"...)
+	*buf = append(*buf, '\n')
 	h.mu.Lock()
 	defer h.mu.Unlock()
-	_, err := h.out.Write(buf)
+	_, err := h.out.Write(*buf)
 
 	return err
 }
 
-func (h *DevLogHandler) appendAttr(buf []byte, a slog.Attr) []byte {
+// orderAttrs reorders attrs so that any keys listed in Options.AttrOrder
+// come first, in that order, followed by the rest in their original order.
+func (h *DevLogHandler) orderAttrs(attrs []slog.Attr) []slog.Attr {
+	order := h.opts.AttrOrder
+	if len(order) == 0 {
+		return attrs
+	}
+
+	used := make([]bool, len(attrs))
+	ordered := make([]slog.Attr, 0, len(attrs))
+	for _, key := range order {
+		for i, a := range attrs {
+			if used[i] || a.Key != key {
+				continue
+			}
+			ordered = append(ordered, a)
+			used[i] = true
+			break
+		}
+	}
+	for i, a := range attrs {
+		if !used[i] {
+			ordered = append(ordered, a)
+		}
+	}
+	return ordered
+}
+
+// appendAttr renders a into buf. groups is the path of groups (outermost
+// first) that a was collected under, and is passed to Options.ReplaceAttr
+// and extended when a itself is a group.
+func (h *DevLogHandler) appendAttr(buf []byte, groups []string, a slog.Attr) []byte {
 	// Resolve the Attr's value before doing anything else
 	a.Value = a.Value.Resolve()
-	// Ignore empty Attrs
-	if a.Equal(slog.Attr{}) {
+
+	// ReplaceAttr never sees the group attr itself, only its members.
+	if rep := h.opts.ReplaceAttr; rep != nil && a.Value.Kind() != slog.KindGroup {
+		a = rep(groups, a)
+		a.Value = a.Value.Resolve()
+	}
+
+	// Drop attrs whose (possibly rewritten) key is empty.
+	if a.Key == "" && a.Value.Kind() != slog.KindGroup {
 		return buf
 	}
+
 	switch a.Value.Kind() {
 	case slog.KindString:
-		keyStr := colorSimple(h.opts.theme.String, a.Key)
-		buf = fmt.Append(buf, keyStr+"="+a.Value.String())
+		buf = h.appendColorKey(buf, h.opts.theme.String, a.Key)
+		buf = append(buf, '=')
+		buf = append(buf, a.Value.String()...)
 	case slog.KindTime:
 		// Write the time in a standard way
-		timeStr := fmt.Sprintf("%s", a.Value.Time().Format(TIME_FORMAT))
-		colorStr := colorSimple(h.opts.theme.Time, timeStr)
-		buf = fmt.Append(buf, colorStr)
+		buf = h.appendColorFunc(buf, h.opts.theme.Time, func(buf []byte) []byte {
+			return a.Value.Time().AppendFormat(buf, h.opts.TimeFormat)
+		})
 	case slog.KindBool:
-		keyStr := colorSimple(h.opts.theme.Bool, a.Key)
-		buf = fmt.Appendf(buf, "%s=%s", keyStr, a.Value)
+		buf = h.appendColorKey(buf, h.opts.theme.Bool, a.Key)
+		buf = append(buf, '=')
+		buf = strconv.AppendBool(buf, a.Value.Bool())
 	case slog.KindInt64:
-		keyStr := colorSimple(h.opts.theme.Int, a.Key)
-		buf = fmt.Appendf(buf, "%s=%s", keyStr, a.Value)
+		buf = h.appendColorKey(buf, h.opts.theme.Int, a.Key)
+		buf = append(buf, '=')
+		buf = strconv.AppendInt(buf, a.Value.Int64(), 10)
+	case slog.KindUint64:
+		buf = h.appendColorKey(buf, h.opts.theme.Uint, a.Key)
+		buf = append(buf, '=')
+		buf = strconv.AppendUint(buf, a.Value.Uint64(), 10)
+	case slog.KindFloat64:
+		buf = h.appendColorKey(buf, h.opts.theme.Float, a.Key)
+		buf = append(buf, '=')
+		buf = strconv.AppendFloat(buf, a.Value.Float64(), 'g', -1, 64)
+	case slog.KindDuration:
+		buf = h.appendColorKey(buf, h.opts.theme.Duration, a.Key)
+		buf = append(buf, '=')
+		if f := h.opts.DurationFormat; f != nil {
+			buf = append(buf, f(a.Value.Duration())...)
+		} else {
+			buf = appendDuration(buf, a.Value.Duration())
+		}
+	case slog.KindAny:
+		if a.Key == slog.LevelKey {
+			if lvl, ok := a.Value.Any().(slog.Level); ok {
+				buf = append(buf, h.handleLvl(lvl)...)
+				buf = append(buf, ' ')
+				return buf
+			}
+		}
+		if a.Key == slog.SourceKey {
+			if src, ok := a.Value.Any().(*slog.Source); ok {
+				buf = h.appendColorFunc(buf, h.opts.theme.SourceFile, func(buf []byte) []byte {
+					buf = append(buf, src.File...)
+					buf = append(buf, ':')
+					buf = strconv.AppendInt(buf, int64(src.Line), 10)
+					return append(buf, ' ')
+				})
+				return buf
+			}
+		}
+		if err, ok := a.Value.Any().(error); ok {
+			buf = h.appendColorKey(buf, h.opts.theme.ErrorAttr, a.Key)
+			buf = append(buf, '=')
+			buf = append(buf, err.Error()...)
+		} else if s, ok := a.Value.Any().(fmt.Stringer); ok {
+			buf = h.appendColorKey(buf, h.opts.theme.Any, a.Key)
+			buf = append(buf, '=')
+			buf = append(buf, s.String()...)
+		} else {
+			buf = h.appendColorKey(buf, h.opts.theme.Any, a.Key)
+			buf = append(buf, '=')
+			buf = append(buf, a.Value.String()...)
+		}
 	case slog.KindGroup:
 		attrs := a.Value.Group()
 		// Ignore empty groups
@@ -209,25 +438,27 @@ func (h *DevLogHandler) appendAttr(buf []byte, a slog.Attr) []byte {
 		}
 		// If key is non empty, write it out
 		// Otherwise inline the attrs
+		childGroups := groups
 		if a.Key != "" {
-			keyStr := colorSimple(h.opts.theme.Group, " "+a.Key+" ")
-			startStr := colorSimple(color{Fg: ANSI_FG_BLACK, Bg: ANSI_BG_GREEN}, " START ")
-			buf = fmt.Appendf(buf, "%s%s ", keyStr, startStr)
+			buf = h.appendColorKey(buf, h.opts.theme.Group, " "+a.Key+" ")
+			buf = h.appendColorKey(buf, color{Fg: ANSI_FG_BLACK, Bg: ANSI_BG_GREEN}, " START ")
+			buf = append(buf, ' ')
+			childGroups = append(append([]string{}, groups...), a.Key)
 		}
 		for _, ga := range attrs {
-			buf = h.appendAttr(buf, ga)
+			buf = h.appendAttr(buf, childGroups, ga)
 		}
 		if a.Key != "" {
-			keyStr := colorSimple(h.opts.theme.Group, " "+a.Key+" ")
-			endStr := colorSimple(color{Fg: ANSI_FG_BLACK, Bg: ANSI_BG_RED}, " END ")
-			buf = fmt.Appendf(buf, "%s%s", keyStr, endStr)
+			buf = h.appendColorKey(buf, h.opts.theme.Group, " "+a.Key+" ")
+			buf = h.appendColorKey(buf, color{Fg: ANSI_FG_BLACK, Bg: ANSI_BG_RED}, " END ")
 		}
 	default:
-		keyStr := colorSimple(h.opts.theme.AttrDefault, a.Key)
-		buf = fmt.Appendf(buf, "%s=%s", keyStr, a.Value)
+		buf = h.appendColorKey(buf, h.opts.theme.AttrDefault, a.Key)
+		buf = append(buf, '=')
+		buf = append(buf, a.Value.String()...)
 	}
 
-	buf = fmt.Append(buf, " ")
+	buf = append(buf, ' ')
 
 	return buf
 }
@@ -260,27 +491,195 @@ func (h *DevLogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
 	return h.withGroupOrAttrs(groupOrAttrs{attrs: attrs})
 }
 
-func handleLvl(lvl slog.Level, t Theme) string {
-	lvlStr := " " + lvl.String() + " "
-	switch lvl {
-	case slog.LevelDebug:
-		return colorSimple(t.Debug, lvlStr)
-	case slog.LevelInfo:
-		return colorSimple(t.Info, lvlStr+" ")
-	case slog.LevelWarn:
-		return colorSimple(t.Warn, lvlStr+" ")
-	case slog.LevelError:
-		return colorSimple(t.Error, lvlStr)
+func (h *DevLogHandler) handleLvl(lvl slog.Level) string {
+	if name, ok := h.opts.LevelNames[lvl]; ok {
+		_, c := h.nearestLvlBucket(lvl)
+		return h.colorSimple(c, " "+name+" ")
+	}
+	if i, ok := lvlLabelIndex(lvl); ok {
+		return h.lvlLabel[i]
 	}
-	return colorSimple(color{Fg: ANSI_FG_BLACK, Bg: ANSI_BG_WHITE}, lvlStr)
+	bucket, c := h.nearestLvlBucket(lvl)
+	label := fmt.Sprintf(" %s%+d ", bucket.String(), int(lvl-bucket))
+	return h.colorSimple(c, label)
 }
 
-func colorSimple(c color, v string) string {
+// nearestLvlBucket returns the standard level (and its theme color) whose
+// value is closest to lvl, with ties favoring the lower bucket. Used to
+// color and label custom levels (e.g. slog.LevelInfo+2) that don't exactly
+// match one of the four standard levels.
+func (h *DevLogHandler) nearestLvlBucket(lvl slog.Level) (slog.Level, color) {
+	buckets := [4]slog.Level{slog.LevelDebug, slog.LevelInfo, slog.LevelWarn, slog.LevelError}
+	colors := [4]color{h.opts.theme.Debug, h.opts.theme.Info, h.opts.theme.Warn, h.opts.theme.Error}
+
+	best := 0
+	bestDist := lvl - buckets[0]
+	if bestDist < 0 {
+		bestDist = -bestDist
+	}
+	for i := 1; i < len(buckets); i++ {
+		dist := lvl - buckets[i]
+		if dist < 0 {
+			dist = -dist
+		}
+		if dist < bestDist {
+			best, bestDist = i, dist
+		}
+	}
+	return buckets[best], colors[best]
+}
+
+// appendDuration appends d to buf formatted the same way as
+// [time.Duration.String], but without allocating, so it can run on the hot
+// Handle path. fmtFrac/fmtInt mirror the unexported helpers the time
+// package's own formatter uses.
+func appendDuration(buf []byte, d time.Duration) []byte {
+	// The largest time.Duration string is roughly "2540400h10m10.000000000s",
+	// which fits comfortably in 32 bytes.
+	var tmp [32]byte
+	w := len(tmp)
+
+	u := uint64(d)
+	neg := d < 0
+	if neg {
+		u = -u
+	}
+
+	if u < uint64(time.Second) {
+		var prec int
+		var unit string
+		switch {
+		case u == 0:
+			return append(buf, "0s"...)
+		case u < uint64(time.Microsecond):
+			unit, prec = "ns", 0
+		case u < uint64(time.Millisecond):
+			unit, prec = "µs", 3
+		default:
+			unit, prec = "ms", 6
+		}
+		w -= len(unit)
+		copy(tmp[w:], unit)
+		w, u = fmtFrac(tmp[:w], u, prec)
+		w = fmtInt(tmp[:w], u)
+	} else {
+		w--
+		tmp[w] = 's'
+
+		w, u = fmtFrac(tmp[:w], u, 9)
+		w = fmtInt(tmp[:w], u%60)
+		u /= 60
+
+		if u > 0 {
+			w--
+			tmp[w] = 'm'
+			w = fmtInt(tmp[:w], u%60)
+			u /= 60
+
+			if u > 0 {
+				w--
+				tmp[w] = 'h'
+				w = fmtInt(tmp[:w], u)
+			}
+		}
+	}
+
+	if neg {
+		w--
+		tmp[w] = '-'
+	}
+
+	return append(buf, tmp[w:]...)
+}
+
+// fmtFrac formats the fraction of v/10**prec (e.g. ".123") into the tail of
+// buf, omitting trailing zeros, and returns the index the fraction starts at
+// along with v with that fraction divided out.
+func fmtFrac(buf []byte, v uint64, prec int) (int, uint64) {
+	print := false
+	w := len(buf)
+	for i := 0; i < prec; i++ {
+		digit := v % 10
+		print = print || digit != 0
+		if print {
+			w--
+			buf[w] = byte(digit) + '0'
+		}
+		v /= 10
+	}
+	if print {
+		w--
+		buf[w] = '.'
+	}
+	return w, v
+}
+
+// fmtInt formats v into the tail of buf and returns the index it starts at.
+func fmtInt(buf []byte, v uint64) int {
+	w := len(buf)
+	if v == 0 {
+		w--
+		buf[w] = '0'
+	} else {
+		for ; v > 0; v /= 10 {
+			w--
+			buf[w] = byte(v%10) + '0'
+		}
+	}
+	return w
+}
+
+// colorSimple wraps v in the ANSI escapes for c, unless color output is
+// disabled, in which case it returns v unchanged so piping to a file or CI
+// log produces plain, grep-friendly text.
+func (h *DevLogHandler) colorSimple(c color, v string) string {
+	if h.noColor {
+		return v
+	}
+	c = normalizeColor(c)
+	return fmt.Sprintf("\033[%s;%sm%s%s", strconv.Itoa(c.Fg), strconv.Itoa(c.Bg), v, ANSI_RESET_COLOR)
+}
+
+// normalizeColor substitutes the terminal default for any Fg/Bg value
+// outside the supported ANSI ranges.
+func normalizeColor(c color) color {
 	if (c.Fg < 30 || c.Fg > 97) || (c.Fg > 37 && c.Fg < 90) {
 		c.Fg = 39
 	}
 	if (c.Bg < 40 || c.Bg > 107) || (c.Bg > 47 && c.Bg < 100) {
 		c.Bg = 49
 	}
-	return fmt.Sprintf("\033[%s;%sm%s%s", strconv.Itoa(c.Fg), strconv.Itoa(c.Bg), v, ANSI_RESET_COLOR)
+	return c
+}
+
+// appendColorKey appends key to buf, wrapped in the ANSI escapes for c
+// unless color output is disabled.
+func (h *DevLogHandler) appendColorKey(buf []byte, c color, key string) []byte {
+	if h.noColor {
+		return append(buf, key...)
+	}
+	buf = appendColorPrefix(buf, c)
+	buf = append(buf, key...)
+	return append(buf, ANSI_RESET_COLOR...)
+}
+
+// appendColorFunc wraps the bytes written by write in the ANSI escapes for
+// c unless color output is disabled, without building an intermediate
+// string first.
+func (h *DevLogHandler) appendColorFunc(buf []byte, c color, write func(buf []byte) []byte) []byte {
+	if h.noColor {
+		return write(buf)
+	}
+	buf = appendColorPrefix(buf, c)
+	buf = write(buf)
+	return append(buf, ANSI_RESET_COLOR...)
+}
+
+func appendColorPrefix(buf []byte, c color) []byte {
+	c = normalizeColor(c)
+	buf = append(buf, "\033["...)
+	buf = strconv.AppendInt(buf, int64(c.Fg), 10)
+	buf = append(buf, ';')
+	buf = strconv.AppendInt(buf, int64(c.Bg), 10)
+	return append(buf, 'm')
 }
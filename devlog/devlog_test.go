@@ -0,0 +1,183 @@
+package devlog
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+var testCtx = context.Background()
+
+// handle renders a single record with the given attrs through h.
+func handle(t *testing.T, h *DevLogHandler, attrs ...slog.Attr) {
+	t.Helper()
+	handleOn(t, h, attrs...)
+}
+
+func newTestHandler(t *testing.T, opts *Options) (*DevLogHandler, *bytes.Buffer) {
+	t.Helper()
+	var buf bytes.Buffer
+	o := Options{NoColor: true}
+	if opts != nil {
+		o = *opts
+		o.NoColor = true
+	}
+	return New(&buf, &o, nil), &buf
+}
+
+func TestWantColorPrecedence(t *testing.T) {
+	var buf bytes.Buffer // not an *os.File, so isTerminal is always false
+
+	tests := []struct {
+		name string
+		opts Options
+		env  map[string]string
+		want bool
+	}{
+		{name: "default no terminal", opts: Options{}, want: false},
+		{name: "NoColor wins over ForceColor", opts: Options{NoColor: true, ForceColor: true}, want: false},
+		{name: "ForceColor option", opts: Options{ForceColor: true}, want: true},
+		{name: "NO_COLOR env", opts: Options{}, env: map[string]string{"NO_COLOR": "1"}, want: false},
+		{name: "FORCE_COLOR env", opts: Options{}, env: map[string]string{"FORCE_COLOR": "1"}, want: true},
+		{name: "option beats env", opts: Options{NoColor: true}, env: map[string]string{"FORCE_COLOR": "1"}, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+			if got := wantColor(&buf, tt.opts); got != tt.want {
+				t.Errorf("wantColor() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHandleReplaceAttrDropsAndRewrites(t *testing.T) {
+	h, buf := newTestHandler(t, &Options{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == "secret" {
+				return slog.Attr{}
+			}
+			if a.Key == "name" {
+				return slog.String("name", "["+strings.Join(groups, "/")+"]"+a.Value.String())
+			}
+			return a
+		},
+	})
+
+	h2 := h.WithGroup("req")
+	handleOn(t, h2, slog.String("name", "alice"), slog.String("secret", "shh"))
+
+	out := buf.String()
+	if strings.Contains(out, "secret") {
+		t.Errorf("expected secret attr to be dropped, got %q", out)
+	}
+	if !strings.Contains(out, "[req]alice") {
+		t.Errorf("expected group-rewritten value, got %q", out)
+	}
+}
+
+// handleOn is like handle but operates on any slog.Handler (so it can be
+// used with the handler returned by WithGroup/WithAttrs).
+func handleOn(t *testing.T, h slog.Handler, attrs ...slog.Attr) {
+	t.Helper()
+	r := slog.NewRecord(time.Time{}, slog.LevelInfo, "hello", 0)
+	r.AddAttrs(attrs...)
+	if err := h.Handle(testCtx, r); err != nil {
+		t.Fatalf("Handle: %v", err)
+	}
+}
+
+func TestHandleKindRendering(t *testing.T) {
+	h, buf := newTestHandler(t, nil)
+	handle(t, h,
+		slog.String("str", "v"),
+		slog.Bool("ok", true),
+		slog.Int("n", -3),
+		slog.Uint64("u", 7),
+		slog.Float64("f", 1.5),
+		slog.Duration("d", 12*time.Millisecond),
+		slog.Any("err", fmt.Errorf("wrap: %w", errors.New("boom"))),
+	)
+	out := buf.String()
+	for _, want := range []string{"str=v", "ok=true", "n=-3", "u=7", "f=1.5", "d=12ms", "err=wrap: boom"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output %q missing %q", out, want)
+		}
+	}
+}
+
+func TestHandleStringer(t *testing.T) {
+	h, buf := newTestHandler(t, nil)
+	handle(t, h, slog.Any("dur", stringerVal{"custom"}))
+	if !strings.Contains(buf.String(), "dur=custom") {
+		t.Errorf("output %q missing stringer rendering", buf.String())
+	}
+}
+
+type stringerVal struct{ s string }
+
+func (s stringerVal) String() string { return s.s }
+
+func TestHandleTimeFormat(t *testing.T) {
+	h, buf := newTestHandler(t, &Options{TimeFormat: "2006-01-02"})
+	r := slog.NewRecord(time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC), slog.LevelInfo, "hi", 0)
+	if err := h.Handle(testCtx, r); err != nil {
+		t.Fatalf("Handle: %v", err)
+	}
+	if !strings.Contains(buf.String(), "2024-03-05") {
+		t.Errorf("output %q missing formatted time", buf.String())
+	}
+}
+
+func TestHandleAttrOrder(t *testing.T) {
+	h, buf := newTestHandler(t, &Options{AttrOrder: []string{"status", "method"}})
+	handle(t, h, slog.String("path", "/x"), slog.Int("status", 200), slog.String("method", "GET"))
+
+	out := buf.String()
+	iStatus := strings.Index(out, "status=")
+	iMethod := strings.Index(out, "method=")
+	iPath := strings.Index(out, "path=")
+	if !(iStatus < iMethod && iMethod < iPath) {
+		t.Errorf("expected order status,method,path, got %q", out)
+	}
+}
+
+func TestHandleLvlBucketsAndTies(t *testing.T) {
+	h, _ := newTestHandler(t, nil)
+
+	tests := []struct {
+		lvl  slog.Level
+		want string
+	}{
+		{slog.LevelDebug, " DEBUG "},
+		{slog.LevelInfo, " INFO  "},
+		{slog.LevelWarn, " WARN  "},
+		{slog.LevelError, " ERROR "},
+		{slog.LevelInfo + 2, " INFO+2 "},
+		// Midpoint between Warn(4) and Error(8) is 6; ties favor the lower bucket.
+		{slog.LevelWarn + 2, " WARN+2 "},
+	}
+	for _, tt := range tests {
+		if got := h.handleLvl(tt.lvl); got != tt.want {
+			t.Errorf("handleLvl(%v) = %q, want %q", tt.lvl, got, tt.want)
+		}
+	}
+}
+
+func TestHandleLevelNamesOverride(t *testing.T) {
+	h, buf := newTestHandler(t, &Options{LevelNames: map[slog.Level]string{slog.LevelError + 4: "FATAL"}})
+	r := slog.NewRecord(time.Time{}, slog.LevelError+4, "boom", 0)
+	if err := h.Handle(testCtx, r); err != nil {
+		t.Fatalf("Handle: %v", err)
+	}
+	if !strings.Contains(buf.String(), " FATAL ") {
+		t.Errorf("output %q missing FATAL label", buf.String())
+	}
+}
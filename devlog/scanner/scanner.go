@@ -0,0 +1,233 @@
+// Package scanner reads JSON or logfmt log lines from an io.Reader and
+// turns each line into a synthetic slog.Record, so that logs emitted by any
+// service can be piped through devlog for colorized, human-readable output.
+package scanner
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// KeyFilter controls which fields of a parsed line are kept as attrs on the
+// resulting record. If Keep is non-empty, only those keys are kept. Otherwise
+// any key in Skip is dropped.
+type KeyFilter struct {
+	Skip map[string]bool
+	Keep map[string]bool
+}
+
+func (f KeyFilter) allowed(key string) bool {
+	if len(f.Keep) > 0 {
+		return f.Keep[key]
+	}
+	if len(f.Skip) > 0 {
+		return !f.Skip[key]
+	}
+	return true
+}
+
+var (
+	timeAliases  = map[string]bool{"ts": true, "time": true, "@timestamp": true}
+	msgAliases   = map[string]bool{"msg": true, "message": true}
+	levelAliases = map[string]bool{"level": true, "lvl": true, "severity": true}
+)
+
+// Scan reads r line by line, auto-detecting whether each line is JSON (it
+// starts with '{') or logfmt, converts it into a slog.Record, and calls fn
+// with the result. Scanning stops at the first error returned by fn.
+func Scan(r io.Reader, filter KeyFilter, fn func(slog.Record) error) error {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 64*1024), 1024*1024)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" {
+			continue
+		}
+		fields := parseLine(line)
+		if err := fn(toRecord(fields, filter)); err != nil {
+			return err
+		}
+	}
+	return sc.Err()
+}
+
+// field is a single key/value pair from a parsed line. Fields are kept in a
+// slice rather than a map so that attr order on the resulting record matches
+// the order fields appeared in the line, instead of Go's randomized map
+// iteration order.
+type field struct {
+	key string
+	val any
+}
+
+// parseLine parses a single log line into an ordered list of fields, trying
+// JSON first when the line looks like an object and falling back to logfmt.
+func parseLine(line string) []field {
+	if strings.HasPrefix(line, "{") {
+		if fields, err := parseJSON(line); err == nil {
+			return fields
+		}
+	}
+	return parseLogfmt(line)
+}
+
+// parseJSON decodes a single JSON object using a token stream instead of
+// json.Unmarshal into a map, so that the resulting fields preserve the key
+// order of the original object.
+func parseJSON(line string) ([]field, error) {
+	dec := json.NewDecoder(strings.NewReader(line))
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	if delim, ok := tok.(json.Delim); !ok || delim != '{' {
+		return nil, fmt.Errorf("scanner: not a JSON object")
+	}
+
+	var fields []field
+	for dec.More() {
+		keyTok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		key, ok := keyTok.(string)
+		if !ok {
+			return nil, fmt.Errorf("scanner: expected string key")
+		}
+		var val any
+		if err := dec.Decode(&val); err != nil {
+			return nil, err
+		}
+		fields = append(fields, field{key: key, val: val})
+	}
+	return fields, nil
+}
+
+func parseLogfmt(line string) []field {
+	var fields []field
+	for _, tok := range splitLogfmt(line) {
+		key, val, ok := strings.Cut(tok, "=")
+		if !ok {
+			continue
+		}
+		fields = append(fields, field{key: key, val: strings.Trim(val, `"`)})
+	}
+	return fields
+}
+
+// splitLogfmt splits a logfmt line into key=value tokens on unquoted
+// spaces, so values containing spaces survive if double-quoted. Other
+// whitespace, such as tabs, does not separate tokens.
+func splitLogfmt(line string) []string {
+	var (
+		toks    []string
+		cur     strings.Builder
+		inQuote bool
+	)
+	for _, r := range line {
+		switch {
+		case r == '"':
+			inQuote = !inQuote
+			cur.WriteRune(r)
+		case r == ' ' && !inQuote:
+			if cur.Len() > 0 {
+				toks = append(toks, cur.String())
+				cur.Reset()
+			}
+		default:
+			cur.WriteRune(r)
+		}
+	}
+	if cur.Len() > 0 {
+		toks = append(toks, cur.String())
+	}
+	return toks
+}
+
+// toRecord maps aliased time/message/level fields onto a slog.Record and
+// keeps the rest (subject to filter) as attrs, in the order they appeared on
+// the line.
+func toRecord(fields []field, filter KeyFilter) slog.Record {
+	var (
+		t   time.Time
+		msg string
+		lvl slog.Level
+	)
+	var attrs []slog.Attr
+	for _, f := range fields {
+		k, v := f.key, f.val
+		switch {
+		case timeAliases[k]:
+			t = parseTime(v)
+		case msgAliases[k]:
+			msg = fmt.Sprint(v)
+		case levelAliases[k]:
+			lvl = parseLevel(v)
+		case filter.allowed(k):
+			attrs = append(attrs, slog.Any(k, v))
+		}
+	}
+	if t.IsZero() {
+		t = time.Now()
+	}
+
+	rec := slog.NewRecord(t, lvl, msg, 0)
+	rec.AddAttrs(attrs...)
+	return rec
+}
+
+func parseTime(v any) time.Time {
+	switch val := v.(type) {
+	case string:
+		if t, err := time.Parse(time.RFC3339Nano, val); err == nil {
+			return t
+		}
+		if f, err := strconv.ParseFloat(val, 64); err == nil {
+			return unixTime(f)
+		}
+	case float64:
+		return unixTime(val)
+	}
+	return time.Time{}
+}
+
+// unixTime converts a numeric timestamp to a time.Time, treating values
+// too large to be seconds since the epoch as milliseconds instead.
+func unixTime(f float64) time.Time {
+	const maxPlausibleSeconds = 1e11 // year ~5138
+	if f > maxPlausibleSeconds {
+		return time.UnixMilli(int64(f))
+	}
+	sec := int64(f)
+	nsec := int64((f - float64(sec)) * 1e9)
+	return time.Unix(sec, nsec)
+}
+
+func parseLevel(v any) slog.Level {
+	switch val := v.(type) {
+	case string:
+		trimmed := strings.TrimSpace(val)
+		if n, err := strconv.Atoi(trimmed); err == nil {
+			return slog.Level(n)
+		}
+		switch strings.ToUpper(trimmed) {
+		case "DEBUG", "DBG":
+			return slog.LevelDebug
+		case "WARN", "WARNING":
+			return slog.LevelWarn
+		case "ERROR", "ERR":
+			return slog.LevelError
+		default:
+			return slog.LevelInfo
+		}
+	case float64:
+		return slog.Level(int(val))
+	}
+	return slog.LevelInfo
+}
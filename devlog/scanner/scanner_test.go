@@ -0,0 +1,113 @@
+package scanner
+
+import (
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func scanOne(t *testing.T, line string, filter KeyFilter) slog.Record {
+	t.Helper()
+	var got *slog.Record
+	err := Scan(strings.NewReader(line), filter, func(r slog.Record) error {
+		got = &r
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Scan: %v", err)
+	}
+	if got == nil {
+		t.Fatalf("Scan: no record produced for %q", line)
+	}
+	return *got
+}
+
+func attrKeys(r slog.Record) []string {
+	var keys []string
+	r.Attrs(func(a slog.Attr) bool {
+		keys = append(keys, a.Key)
+		return true
+	})
+	return keys
+}
+
+func TestScanJSONAliasesAndOrder(t *testing.T) {
+	line := `{"time":"2024-03-05T10:00:00Z","level":"warn","msg":"hi","b":1,"a":2,"c":3}`
+	r := scanOne(t, line, KeyFilter{})
+
+	if r.Message != "hi" {
+		t.Errorf("Message = %q, want %q", r.Message, "hi")
+	}
+	if r.Level != slog.LevelWarn {
+		t.Errorf("Level = %v, want %v", r.Level, slog.LevelWarn)
+	}
+	if got, want := attrKeys(r), []string{"b", "a", "c"}; !equalSlices(got, want) {
+		t.Errorf("attr order = %v, want %v", got, want)
+	}
+}
+
+func TestScanLogfmtAliasesAndOrder(t *testing.T) {
+	line := `level=error msg="bad thing" d=1 c=2 b=3`
+	r := scanOne(t, line, KeyFilter{})
+
+	if r.Message != "bad thing" {
+		t.Errorf("Message = %q, want %q", r.Message, "bad thing")
+	}
+	if r.Level != slog.LevelError {
+		t.Errorf("Level = %v, want %v", r.Level, slog.LevelError)
+	}
+	if got, want := attrKeys(r), []string{"d", "c", "b"}; !equalSlices(got, want) {
+		t.Errorf("attr order = %v, want %v", got, want)
+	}
+}
+
+func TestParseLevelNumericString(t *testing.T) {
+	// logfmt values are always strings, so a custom numeric level must still
+	// parse to the right slog.Level instead of falling back to Info.
+	line := `level=12 msg="custom level"`
+	r := scanOne(t, line, KeyFilter{})
+	if want := slog.Level(12); r.Level != want {
+		t.Errorf("Level = %v, want %v", r.Level, want)
+	}
+}
+
+func TestParseLevelWords(t *testing.T) {
+	tests := map[string]slog.Level{
+		"debug": slog.LevelDebug,
+		"DBG":   slog.LevelDebug,
+		"warn":  slog.LevelWarn,
+		"ERR":   slog.LevelError,
+		"info":  slog.LevelInfo,
+	}
+	for s, want := range tests {
+		if got := parseLevel(s); got != want {
+			t.Errorf("parseLevel(%q) = %v, want %v", s, got, want)
+		}
+	}
+}
+
+func TestKeyFilter(t *testing.T) {
+	line := `level=info msg="hi" a=1 b=2 c=3`
+
+	r := scanOne(t, line, KeyFilter{Skip: map[string]bool{"b": true}})
+	if got, want := attrKeys(r), []string{"a", "c"}; !equalSlices(got, want) {
+		t.Errorf("Skip filter: attr order = %v, want %v", got, want)
+	}
+
+	r = scanOne(t, line, KeyFilter{Keep: map[string]bool{"c": true}})
+	if got, want := attrKeys(r), []string{"c"}; !equalSlices(got, want) {
+		t.Errorf("Keep filter: attr order = %v, want %v", got, want)
+	}
+}
+
+func equalSlices(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}